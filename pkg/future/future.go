@@ -0,0 +1,129 @@
+// Package future provides a Future/Promise primitive for a single
+// deferred result, complementing pkg/pipeline for callers that want one
+// value back instead of a stream — e.g. "the sum of the first N
+// generated numbers computed asynchronously".
+package future
+
+import (
+	"context"
+	"sync"
+)
+
+// Future represents a value of type T that will become available at
+// some point in the future. It is safe for multiple goroutines to call
+// Await concurrently.
+type Future[T any] struct {
+	done chan struct{}
+	once sync.Once
+	val  T
+	err  error
+}
+
+// New starts fn in its own goroutine and returns a Future that resolves
+// to its result. If ctx is done before fn returns, the Future resolves
+// early with ctx.Err(); fn's goroutine is left to finish on its own,
+// since fn itself has no way to observe ctx.
+func New[T any](ctx context.Context, fn func() (T, error)) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
+
+	result := make(chan struct{})
+	var v T
+	var err error
+	go func() {
+		v, err = fn()
+		close(result)
+	}()
+
+	go func() {
+		select {
+		case <-result:
+			f.publish(v, err)
+		case <-ctx.Done():
+			var zero T
+			f.publish(zero, ctx.Err())
+		}
+	}()
+
+	return f
+}
+
+func (f *Future[T]) publish(v T, err error) {
+	f.once.Do(func() {
+		f.val = v
+		f.err = err
+		close(f.done)
+	})
+}
+
+// Await blocks until the Future resolves and returns its value and
+// error. It may be called concurrently from multiple goroutines.
+func (f *Future[T]) Await() (T, error) {
+	<-f.done
+	return f.val, f.err
+}
+
+// Done returns a channel that is closed once the Future resolves.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Then builds a new Future that resolves to fn applied to f's result
+// once f resolves successfully. An error from f is propagated as-is
+// without calling fn.
+func Then[T, U any](f *Future[T], fn func(T) U) *Future[U] {
+	return New(context.Background(), func() (U, error) {
+		v, err := f.Await()
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+		return fn(v), nil
+	})
+}
+
+// All returns a Future that resolves to the results of every future in
+// order once all of them have resolved, or to the first error
+// encountered.
+func All[T any](ctx context.Context, futures ...*Future[T]) *Future[[]T] {
+	return New(ctx, func() ([]T, error) {
+		results := make([]T, len(futures))
+		for i, fut := range futures {
+			v, err := fut.Await()
+			if err != nil {
+				return nil, err
+			}
+			results[i] = v
+		}
+		return results, nil
+	})
+}
+
+// Any returns a Future that resolves to the first successful result
+// among futures, or to the last error if all of them fail.
+func Any[T any](ctx context.Context, futures ...*Future[T]) *Future[T] {
+	return New(ctx, func() (T, error) {
+		type outcome struct {
+			v   T
+			err error
+		}
+		results := make(chan outcome, len(futures))
+		for _, fut := range futures {
+			fut := fut
+			go func() {
+				v, err := fut.Await()
+				results <- outcome{v, err}
+			}()
+		}
+
+		var zero T
+		var lastErr error
+		for range futures {
+			o := <-results
+			if o.err == nil {
+				return o.v, nil
+			}
+			lastErr = o.err
+		}
+		return zero, lastErr
+	})
+}
@@ -0,0 +1,108 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-project-sprint-9/pkg/future"
+)
+
+func TestAwaitReturnsValue(t *testing.T) {
+	f := future.New(context.Background(), func() (int, error) {
+		return 42, nil
+	})
+	v, err := f.Await()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != 42 {
+		t.Fatalf("got %d, want 42", v)
+	}
+}
+
+func TestAwaitConcurrentCallers(t *testing.T) {
+	f := future.New(context.Background(), func() (int, error) {
+		time.Sleep(10 * time.Millisecond)
+		return 7, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := f.Await()
+			if err != nil || v != 7 {
+				t.Errorf("got (%d, %v), want (7, nil)", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestContextCancellationPropagates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := future.New(ctx, func() (int, error) {
+		time.Sleep(time.Second)
+		return 1, nil
+	})
+	cancel()
+
+	_, err := f.Await()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+}
+
+func TestThenChainsResult(t *testing.T) {
+	f := future.New(context.Background(), func() (int, error) { return 3, nil })
+	g := future.Then(f, func(v int) int { return v * 2 })
+
+	v, err := g.Await()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != 6 {
+		t.Fatalf("got %d, want 6", v)
+	}
+}
+
+func TestAllWaitsForEveryFuture(t *testing.T) {
+	f1 := future.New(context.Background(), func() (int, error) { return 1, nil })
+	f2 := future.New(context.Background(), func() (int, error) { return 2, nil })
+	f3 := future.New(context.Background(), func() (int, error) { return 3, nil })
+
+	all := future.All(context.Background(), f1, f2, f3)
+	got, err := all.Await()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []int{1, 2, 3}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestAnyReturnsFirstSuccess(t *testing.T) {
+	failing := future.New(context.Background(), func() (int, error) {
+		return 0, errors.New("boom")
+	})
+	succeeding := future.New(context.Background(), func() (int, error) {
+		time.Sleep(5 * time.Millisecond)
+		return 9, nil
+	})
+
+	any := future.Any(context.Background(), failing, succeeding)
+	v, err := any.Await()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != 9 {
+		t.Fatalf("got %d, want 9", v)
+	}
+}
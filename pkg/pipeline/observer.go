@@ -0,0 +1,163 @@
+package pipeline
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Observer is notified of pipeline events as they happen, so that
+// metrics and invariant checks can be plugged in without editing the
+// pipeline stages themselves.
+type Observer interface {
+	OnProduced(v int64)
+	OnConsumed(workerID int, v int64)
+	OnClosed(stage string)
+}
+
+// NoopObserver implements Observer by doing nothing. It's the default
+// used when a nil Observer is passed to a stage constructor.
+type NoopObserver struct{}
+
+func (NoopObserver) OnProduced(int64)      {}
+func (NoopObserver) OnConsumed(int, int64) {}
+func (NoopObserver) OnClosed(string)       {}
+
+// MultiObserver fans every event out to a list of Observers, so a stage
+// that only accepts one Observer can still be watched by several.
+type MultiObserver []Observer
+
+func (m MultiObserver) OnProduced(v int64) {
+	for _, o := range m {
+		o.OnProduced(v)
+	}
+}
+
+func (m MultiObserver) OnConsumed(workerID int, v int64) {
+	for _, o := range m {
+		o.OnConsumed(workerID, v)
+	}
+}
+
+func (m MultiObserver) OnClosed(stage string) {
+	for _, o := range m {
+		o.OnClosed(stage)
+	}
+}
+
+// AtomicCounterObserver tracks aggregate produced/consumed counts and
+// sums with atomic operations — the same bookkeeping main used to do by
+// hand with inputSum/inputCount.
+type AtomicCounterObserver struct {
+	ProducedCount int64
+	ProducedSum   int64
+	ConsumedCount int64
+	ConsumedSum   int64
+}
+
+func (o *AtomicCounterObserver) OnProduced(v int64) {
+	atomic.AddInt64(&o.ProducedCount, 1)
+	atomic.AddInt64(&o.ProducedSum, v)
+}
+
+func (o *AtomicCounterObserver) OnConsumed(_ int, v int64) {
+	atomic.AddInt64(&o.ConsumedCount, 1)
+	atomic.AddInt64(&o.ConsumedSum, v)
+}
+
+func (o *AtomicCounterObserver) OnClosed(string) {}
+
+// HistogramObserver records produced/consumed event timestamps to
+// approximate a Prometheus-style rate, and tallies a per-worker
+// distribution of how many items each worker has handled — the
+// Observer-based replacement for the old amounts slice.
+type HistogramObserver struct {
+	mu        sync.Mutex
+	produced  []time.Time
+	consumed  []time.Time
+	perWorker map[int]int64
+}
+
+// NewHistogramObserver returns a ready-to-use HistogramObserver.
+func NewHistogramObserver() *HistogramObserver {
+	return &HistogramObserver{perWorker: make(map[int]int64)}
+}
+
+func (o *HistogramObserver) OnProduced(int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.produced = append(o.produced, time.Now())
+}
+
+func (o *HistogramObserver) OnConsumed(workerID int, _ int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.consumed = append(o.consumed, time.Now())
+	o.perWorker[workerID]++
+}
+
+func (o *HistogramObserver) OnClosed(string) {}
+
+// ProducedRate returns the observed OnProduced events per second over
+// the span between the first and the latest OnProduced event.
+func (o *HistogramObserver) ProducedRate() float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return rate(o.produced)
+}
+
+// ConsumedRate returns the observed OnConsumed events per second over
+// the span between the first and the latest OnConsumed event.
+func (o *HistogramObserver) ConsumedRate() float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return rate(o.consumed)
+}
+
+func rate(events []time.Time) float64 {
+	if len(events) < 2 {
+		return 0
+	}
+	elapsed := events[len(events)-1].Sub(events[0]).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(len(events)) / elapsed
+}
+
+// PerWorkerDistribution returns a copy of how many items each worker ID
+// has consumed so far.
+func (o *HistogramObserver) PerWorkerDistribution() map[int]int64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := make(map[int]int64, len(o.perWorker))
+	for k, v := range o.perWorker {
+		out[k] = v
+	}
+	return out
+}
+
+// InvariantObserver fails a run if the sum of produced values ever
+// diverges from the sum of consumed values once the pipeline has fully
+// drained, expressing the sum/count checks main used to perform by hand
+// as a reusable Observer.
+type InvariantObserver struct {
+	producedSum int64
+	consumedSum int64
+}
+
+func (o *InvariantObserver) OnProduced(v int64)        { atomic.AddInt64(&o.producedSum, v) }
+func (o *InvariantObserver) OnConsumed(_ int, v int64) { atomic.AddInt64(&o.consumedSum, v) }
+func (o *InvariantObserver) OnClosed(string)           {}
+
+// Check returns an error if the produced and consumed sums disagree.
+// Call it only after the pipeline has fully drained.
+func (o *InvariantObserver) Check() error {
+	produced := atomic.LoadInt64(&o.producedSum)
+	consumed := atomic.LoadInt64(&o.consumedSum)
+	if produced != consumed {
+		return fmt.Errorf("pipeline: invariant violated: producedSum=%d != consumedSum=%d", produced, consumed)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package pipeline_test
+
+import (
+	"context"
+	"testing"
+
+	"go-project-sprint-9/pkg/pipeline"
+)
+
+// countAndCancelObserver cancels ctx once it has seen limit produced
+// values, giving a bounded run whose sum/count invariant must hold
+// exactly (as opposed to a wall-clock timeout, which may cancel while a
+// value is still in flight).
+type countAndCancelObserver struct {
+	limit  int64
+	seen   int64
+	cancel context.CancelFunc
+}
+
+func (o *countAndCancelObserver) OnProduced(int64) {
+	o.seen++
+	if o.seen >= o.limit {
+		o.cancel()
+	}
+}
+func (o *countAndCancelObserver) OnConsumed(int, int64) {}
+func (o *countAndCancelObserver) OnClosed(string)       {}
+
+func TestInvariantObserverPassesOnBalancedPipeline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	invariant := &pipeline.InvariantObserver{}
+	limiter := &countAndCancelObserver{limit: 500, cancel: cancel}
+	produceObs := pipeline.MultiObserver{invariant, limiter}
+
+	// Stages only need to stop once the generator's channel closes, not
+	// abandon early — pass a done that's never closed so no in-flight
+	// value is dropped.
+	neverDone := make(chan struct{})
+
+	in := pipeline.Generator(ctx, produceObs)
+	outs := pipeline.FanOut(neverDone, in, 3)
+	workers := make([]<-chan int64, len(outs))
+	for i, out := range outs {
+		workers[i] = pipeline.Worker(neverDone, i, out, invariant)
+	}
+	merged := pipeline.FanIn(neverDone, workers...)
+
+	for range merged {
+	}
+
+	if err := invariant.Check(); err != nil {
+		t.Fatalf("unexpected invariant failure: %v", err)
+	}
+}
+
+func TestInvariantObserverFailsOnImbalance(t *testing.T) {
+	invariant := &pipeline.InvariantObserver{}
+	invariant.OnProduced(10)
+	invariant.OnConsumed(0, 4)
+
+	if err := invariant.Check(); err == nil {
+		t.Fatal("expected an invariant violation error, got nil")
+	}
+}
+
+func TestHistogramObserverTracksPerWorkerDistribution(t *testing.T) {
+	hist := pipeline.NewHistogramObserver()
+	hist.OnConsumed(0, 1)
+	hist.OnConsumed(0, 2)
+	hist.OnConsumed(1, 3)
+
+	dist := hist.PerWorkerDistribution()
+	if dist[0] != 2 || dist[1] != 1 {
+		t.Fatalf("unexpected distribution: %+v", dist)
+	}
+}
+
+func TestMultiObserverFansOutToEveryObserver(t *testing.T) {
+	a := &pipeline.AtomicCounterObserver{}
+	b := &pipeline.AtomicCounterObserver{}
+	multi := pipeline.MultiObserver{a, b}
+
+	multi.OnProduced(5)
+	multi.OnConsumed(0, 5)
+	multi.OnClosed("generator")
+
+	if a.ProducedSum != 5 || b.ProducedSum != 5 {
+		t.Fatalf("expected both observers to see the produced value, got a=%d b=%d", a.ProducedSum, b.ProducedSum)
+	}
+	if a.ConsumedSum != 5 || b.ConsumedSum != 5 {
+		t.Fatalf("expected both observers to see the consumed value, got a=%d b=%d", a.ConsumedSum, b.ConsumedSum)
+	}
+}
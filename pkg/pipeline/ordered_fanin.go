@@ -0,0 +1,93 @@
+package pipeline
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// Seq tags a value with its position in the original generator sequence,
+// so that OrderedFanIn can reassemble values merged from several workers
+// back into that order.
+type Seq[T any] struct {
+	N uint64
+	V T
+}
+
+// Tag wraps values read from in with a monotonically increasing
+// sequence number starting at 0.
+func Tag[T any](in <-chan T) <-chan Seq[T] {
+	out := make(chan Seq[T])
+	go func() {
+		defer close(out)
+		var n uint64
+		for v := range in {
+			out <- Seq[T]{N: n, V: v}
+			n++
+		}
+	}()
+	return out
+}
+
+// OrderedFanIn merges in together with every channel in workers —
+// typically the per-worker outputs of a FanOut(Tag(Generator(...))) —
+// and reassembles the values in original sequence order using a
+// min-heap keyed by Seq.N and a "next expected" cursor. Out-of-order
+// arrivals are buffered until they can be emitted in order, bounded by
+// window positions ahead of the cursor; an item arriving more than
+// window positions ahead of the cursor (so the item the cursor waits for
+// is more than window positions late) is reported on the returned error
+// channel and stops reassembly.
+func OrderedFanIn[T any](in <-chan Seq[T], workers []<-chan Seq[T], window int) (<-chan T, <-chan error) {
+	sources := make([]<-chan Seq[T], 0, len(workers)+1)
+	sources = append(sources, in)
+	sources = append(sources, workers...)
+	merged := FanIn[Seq[T]](nil, sources...)
+
+	out := make(chan T)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		pending := &seqHeap[T]{}
+		heap.Init(pending)
+		var next uint64
+
+		for item := range merged {
+			if item.N < next {
+				// Already emitted; a duplicate tag, ignore it.
+				continue
+			}
+			if item.N-next > uint64(window) {
+				errc <- fmt.Errorf("pipeline: item %d arrived %d positions past the expected %d, exceeds window %d", item.N, item.N-next, next, window)
+				return
+			}
+			heap.Push(pending, item)
+			for pending.Len() > 0 && (*pending)[0].N == next {
+				v := heap.Pop(pending).(Seq[T])
+				out <- v.V
+				next++
+			}
+		}
+	}()
+
+	return out, errc
+}
+
+// seqHeap is a container/heap.Interface over Seq values, ordered by
+// sequence number, so the smallest pending sequence number is always
+// at the root.
+type seqHeap[T any] []Seq[T]
+
+func (h seqHeap[T]) Len() int            { return len(h) }
+func (h seqHeap[T]) Less(i, j int) bool  { return h[i].N < h[j].N }
+func (h seqHeap[T]) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *seqHeap[T]) Push(x any)         { *h = append(*h, x.(Seq[T])) }
+func (h *seqHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,87 @@
+package pipeline_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-project-sprint-9/pkg/pipeline"
+)
+
+func TestOrderedFanInPreservesSequence(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	done := ctx.Done()
+
+	const n = 200
+	values := make(chan int64)
+	go func() {
+		defer close(values)
+		for i := int64(0); i < n; i++ {
+			select {
+			case values <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	tagged := pipeline.Tag[int64](values)
+	empty := make(chan pipeline.Seq[int64])
+	close(empty)
+
+	// Route each tagged item through a worker that reorders them by
+	// sleeping 0-2µs depending on its sequence number modulo 3, so
+	// items can reach the merge out of order.
+	outs := pipeline.FanOut(done, tagged, 4)
+	workers := make([]<-chan pipeline.Seq[int64], len(outs))
+	for i, out := range outs {
+		out := out
+		reordered := make(chan pipeline.Seq[int64])
+		go func() {
+			defer close(reordered)
+			for s := range out {
+				time.Sleep(time.Duration(s.N%3) * time.Microsecond)
+				reordered <- s
+			}
+		}()
+		workers[i] = reordered
+	}
+
+	merged, errc := pipeline.OrderedFanIn[int64](empty, workers, n)
+
+	var got []int64
+	for v := range merged {
+		got = append(got, v)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != n {
+		t.Fatalf("got %d values, want %d", len(got), n)
+	}
+	for i, v := range got {
+		if v != int64(i) {
+			t.Fatalf("out of order at position %d: got %d, want %d", i, v, i)
+		}
+	}
+}
+
+func TestOrderedFanInErrorsBeyondWindow(t *testing.T) {
+	worker := make(chan pipeline.Seq[int64], 2)
+	worker <- pipeline.Seq[int64]{N: 0, V: 10}
+	worker <- pipeline.Seq[int64]{N: 5, V: 15}
+	close(worker)
+
+	empty := make(chan pipeline.Seq[int64])
+	close(empty)
+
+	merged, errc := pipeline.OrderedFanIn[int64](empty, []<-chan pipeline.Seq[int64]{worker}, 2)
+
+	for range merged {
+	}
+	if err := <-errc; err == nil {
+		t.Fatal("expected a window-exceeded error, got nil")
+	}
+}
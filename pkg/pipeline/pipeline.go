@@ -0,0 +1,152 @@
+// Package pipeline provides composable building blocks for Go pipelines,
+// modeled on the patterns described in "Go Concurrency Patterns: Pipelines
+// and cancellation" (https://go.dev/blog/pipelines). Every stage owns the
+// channel it writes to and closes it once it is done writing; every stage
+// that reads from an upstream channel also selects on a done channel so
+// that an abandoned downstream stage doesn't leave upstream goroutines
+// blocked forever on a send.
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Generator emits the sequence 1, 2, 3, ... on the returned channel,
+// reporting every value to obs right after it is sent. It stops and
+// closes the channel as soon as ctx is done. A nil obs is treated as
+// NoopObserver.
+func Generator(ctx context.Context, obs Observer) <-chan int64 {
+	if obs == nil {
+		obs = NoopObserver{}
+	}
+	out := make(chan int64)
+	go func() {
+		defer close(out)
+		defer obs.OnClosed("generator")
+		var i int64 = 1
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- i:
+				obs.OnProduced(i)
+				i++
+			}
+		}
+	}()
+	return out
+}
+
+// Worker reads values from in, reports each one to obs under the given
+// worker id, and forwards it unchanged to the returned channel. It is
+// the Observer-aware counterpart to Stage for the int64 pipelines this
+// package was originally built for. A nil obs is treated as
+// NoopObserver.
+func Worker(done <-chan struct{}, id int, in <-chan int64, obs Observer) <-chan int64 {
+	if obs == nil {
+		obs = NoopObserver{}
+	}
+	out := make(chan int64)
+	go func() {
+		defer close(out)
+		defer obs.OnClosed(fmt.Sprintf("worker-%d", id))
+		for v := range in {
+			obs.OnConsumed(id, v)
+			select {
+			case out <- v:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Stage applies fn to every value read from in and writes the result to
+// the returned channel. It closes its outbound channel once in is
+// closed, or, if done is closed, at its next attempt to send; done is not
+// watched while waiting on in.
+func Stage[T any](done <-chan struct{}, in <-chan T, fn func(T) T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for v := range in {
+			select {
+			case out <- fn(v):
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FanOut distributes the values of in across n outbound channels so that
+// n goroutines can work on them concurrently. Every returned channel is
+// closed once in is closed, or, if done is closed, at the next attempt to
+// send; done is not watched while waiting on in.
+func FanOut[T any](done <-chan struct{}, in <-chan T, n int) []<-chan T {
+	outs := make([]chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+	}
+
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+		i := 0
+		for v := range in {
+			select {
+			case outs[i] <- v:
+			case <-done:
+				return
+			}
+			i = (i + 1) % n
+		}
+	}()
+
+	result := make([]<-chan T, n)
+	for i, out := range outs {
+		result[i] = out
+	}
+	return result
+}
+
+// FanIn merges several inbound channels onto a single outbound channel.
+// The returned channel is closed once every forwarder has stopped; a
+// forwarder stops when its inbound channel is closed, or, if done is
+// closed, at its next attempt to send. Closing only happens after every forwarding goroutine
+// has actually returned, so a forwarder can never send on an already
+// closed out.
+func FanIn[T any](done <-chan struct{}, channels ...<-chan T) <-chan T {
+	out := make(chan T)
+
+	var wg sync.WaitGroup
+	forward := func(c <-chan T) {
+		defer wg.Done()
+		for v := range c {
+			select {
+			case out <- v:
+			case <-done:
+				return
+			}
+		}
+	}
+
+	wg.Add(len(channels))
+	for _, c := range channels {
+		go forward(c)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
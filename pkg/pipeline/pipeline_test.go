@@ -0,0 +1,102 @@
+package pipeline_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"go-project-sprint-9/pkg/pipeline"
+)
+
+// waitGoroutines gives leaked goroutines a chance to actually unwind
+// before we snapshot runtime.NumGoroutine.
+func waitGoroutines() {
+	for i := 0; i < 10; i++ {
+		runtime.Gosched()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestEarlyCancellationDoesNotLeakGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	in := pipeline.Generator(ctx, nil)
+	outs := pipeline.FanOut(done, in, 4)
+	merged := pipeline.FanIn(done, outs...)
+
+	// Read a handful of values, then abandon the pipeline early.
+	for i := 0; i < 5; i++ {
+		<-merged
+	}
+	close(done)
+	cancel()
+
+	waitGoroutines()
+
+	after := runtime.NumGoroutine()
+	if after > before {
+		t.Fatalf("goroutine leak: before=%d after=%d", before, after)
+	}
+}
+
+// cancelAfterObserver stops the generator once it has produced limit
+// values, by cancelling ctx from inside OnProduced. Because Generator
+// only calls OnProduced after a value has already been handed to its
+// outbound channel, the limit-th value itself is never lost.
+type cancelAfterObserver struct {
+	*pipeline.AtomicCounterObserver
+	limit  int64
+	cancel context.CancelFunc
+}
+
+func (o *cancelAfterObserver) OnProduced(v int64) {
+	o.AtomicCounterObserver.OnProduced(v)
+	if o.ProducedCount >= o.limit {
+		o.cancel()
+	}
+}
+
+func TestPipelinePreservesSumAndCount(t *testing.T) {
+	const want = 500
+
+	for _, n := range []int{1, 3, 5} {
+		n := n
+		t.Run(fmt.Sprintf("workers=%d", n), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			counter := &cancelAfterObserver{AtomicCounterObserver: &pipeline.AtomicCounterObserver{}, limit: want, cancel: cancel}
+
+			// Stages only need to stop once the generator's channel
+			// closes, not abandon early — pass a done that's never
+			// closed so no in-flight value is dropped.
+			neverDone := make(chan struct{})
+
+			in := pipeline.Generator(ctx, counter)
+
+			outs := pipeline.FanOut(neverDone, in, n)
+			stages := make([]<-chan int64, n)
+			for i, out := range outs {
+				stages[i] = pipeline.Stage(neverDone, out, func(v int64) int64 { return v })
+			}
+			merged := pipeline.FanIn(neverDone, stages...)
+
+			var consumedSum, consumedCount int64
+			for v := range merged {
+				consumedSum += v
+				consumedCount++
+			}
+
+			if consumedSum != counter.ProducedSum {
+				t.Fatalf("sum mismatch: produced=%d consumed=%d", counter.ProducedSum, consumedSum)
+			}
+			if consumedCount != counter.ProducedCount {
+				t.Fatalf("count mismatch: produced=%d consumed=%d", counter.ProducedCount, consumedCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+// Package pool implements a generic buffered worker pool. Unlike the
+// single shared, unbuffered input channel the original pipeline used —
+// which left work distribution to whichever worker happened to win the
+// race on a given tick — each worker here owns its own buffered queue, so
+// skew can be measured (via Stats) and, optionally, corrected (via
+// Rebalance).
+package pool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WorkerStats holds the per-worker counters exposed by Stats.
+type WorkerStats struct {
+	Processed int64
+	// Latency is the cumulative time spent executing the pool's
+	// processing function.
+	Latency time.Duration
+	// Idle is the cumulative time the worker spent waiting for work.
+	Idle time.Duration
+	// QueueLen is a snapshot of how many items are currently queued for
+	// this worker.
+	QueueLen int
+	// Dropped counts items this worker finished processing but could
+	// not deliver to Results because ctx was done and nobody was
+	// draining it.
+	Dropped int64
+}
+
+// Snapshot is a point-in-time view of every worker's statistics.
+type Snapshot struct {
+	PerWorker []WorkerStats
+	Total     int64
+}
+
+type workerStats struct {
+	processed int64 // atomic
+	latency   int64 // atomic, nanoseconds
+	idle      int64 // atomic, nanoseconds
+	dropped   int64 // atomic
+}
+
+// WorkerPool runs size workers, each applying fn to values submitted via
+// Submit and publishing results on the channel returned by Results.
+type WorkerPool[T, R any] struct {
+	fn     func(T) R
+	queues []chan T
+	out    chan R
+	stats  []*workerStats
+
+	rebalance bool
+	threshold int
+
+	next uint64 // atomic round-robin cursor for Submit
+
+	wg        sync.WaitGroup
+	monitorWg sync.WaitGroup
+}
+
+// Option configures a WorkerPool at construction time.
+type Option[T, R any] func(*WorkerPool[T, R])
+
+// WithRebalance enables the rebalancing monitor: whenever a worker's
+// queue length exceeds threshold, one pending item is moved to the
+// least-loaded worker.
+func WithRebalance[T, R any](threshold int) Option[T, R] {
+	return func(p *WorkerPool[T, R]) {
+		p.rebalance = true
+		p.threshold = threshold
+	}
+}
+
+// New creates a WorkerPool with size workers, each with an inbound queue
+// of capacity inBuf, and a results channel of capacity outBuf.
+func New[T, R any](size, inBuf, outBuf int, fn func(T) R, opts ...Option[T, R]) *WorkerPool[T, R] {
+	p := &WorkerPool[T, R]{
+		fn:     fn,
+		queues: make([]chan T, size),
+		out:    make(chan R, outBuf),
+		stats:  make([]*workerStats, size),
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan T, inBuf)
+		p.stats[i] = &workerStats{}
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Start launches the worker goroutines (and, if enabled, the rebalancing
+// monitor). It returns immediately; workers run until ctx is done or
+// every queue has drained, finishing whatever item they've already
+// dequeued before stopping.
+func (p *WorkerPool[T, R]) Start(ctx context.Context) {
+	for id, queue := range p.queues {
+		p.wg.Add(1)
+		go p.runWorker(ctx, id, queue)
+	}
+	go func() {
+		p.wg.Wait()
+		close(p.out)
+	}()
+
+	if p.rebalance {
+		p.monitorWg.Add(1)
+		go p.runMonitor(ctx)
+	}
+}
+
+// runWorker honors ctx.Done() both while waiting for the next item and
+// while delivering a finished result, so it can never block forever on
+// an abandoned Results(). A result that's computed but can't be
+// delivered because ctx is done is counted in stats.dropped instead of
+// silently vanishing, so callers can still reconcile exactly:
+// submitted == consumed + stillQueued + dropped.
+func (p *WorkerPool[T, R]) runWorker(ctx context.Context, id int, queue chan T) {
+	defer p.wg.Done()
+	stats := p.stats[id]
+	idleStart := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case v, ok := <-queue:
+			if !ok {
+				return
+			}
+			atomic.AddInt64(&stats.idle, int64(time.Since(idleStart)))
+
+			start := time.Now()
+			r := p.fn(v)
+			atomic.AddInt64(&stats.latency, int64(time.Since(start)))
+
+			select {
+			case p.out <- r:
+				atomic.AddInt64(&stats.processed, 1)
+			case <-ctx.Done():
+				atomic.AddInt64(&stats.dropped, 1)
+				return
+			}
+			idleStart = time.Now()
+		}
+	}
+}
+
+// runMonitor periodically moves one item from the most loaded queue to
+// the least loaded one whenever the former exceeds p.threshold.
+func (p *WorkerPool[T, R]) runMonitor(ctx context.Context) {
+	defer p.monitorWg.Done()
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.rebalanceOnce(ctx)
+		}
+	}
+}
+
+func (p *WorkerPool[T, R]) rebalanceOnce(ctx context.Context) {
+	busiest, lightest := -1, -1
+	for i, q := range p.queues {
+		l := len(q)
+		if busiest == -1 || l > len(p.queues[busiest]) {
+			busiest = i
+		}
+		if lightest == -1 || l < len(p.queues[lightest]) {
+			lightest = i
+		}
+	}
+	if busiest == lightest || len(p.queues[busiest]) <= p.threshold {
+		return
+	}
+	select {
+	case v := <-p.queues[busiest]:
+		select {
+		case p.queues[lightest] <- v:
+		default:
+			// Lightest queue is full again; put the item back rather
+			// than drop it. If ctx is done, the worker that owned
+			// busiest may already have exited and nothing will ever
+			// drain it again, so give up instead of blocking forever.
+			select {
+			case p.queues[busiest] <- v:
+			case <-ctx.Done():
+			}
+		}
+	default:
+	}
+}
+
+// Submit enqueues v for processing, distributing across workers
+// round-robin. It blocks (respecting ctx) when the chosen worker's
+// queue is full, providing backpressure to the caller.
+func (p *WorkerPool[T, R]) Submit(ctx context.Context, v T) error {
+	i := int(atomic.AddUint64(&p.next, 1)-1) % len(p.queues)
+	select {
+	case p.queues[i] <- v:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close signals that no more values will be submitted, allowing workers
+// to drain their queues and exit once empty.
+func (p *WorkerPool[T, R]) Close() {
+	for _, q := range p.queues {
+		close(q)
+	}
+}
+
+// Results returns the channel on which processed results are published.
+// It is closed once every worker has exited.
+func (p *WorkerPool[T, R]) Results() <-chan R {
+	return p.out
+}
+
+// Stats returns a snapshot of the current per-worker counters.
+func (p *WorkerPool[T, R]) Stats() Snapshot {
+	snap := Snapshot{PerWorker: make([]WorkerStats, len(p.stats))}
+	for i, s := range p.stats {
+		processed := atomic.LoadInt64(&s.processed)
+		snap.PerWorker[i] = WorkerStats{
+			Processed: processed,
+			Latency:   time.Duration(atomic.LoadInt64(&s.latency)),
+			Idle:      time.Duration(atomic.LoadInt64(&s.idle)),
+			QueueLen:  len(p.queues[i]),
+			Dropped:   atomic.LoadInt64(&s.dropped),
+		}
+		snap.Total += processed
+	}
+	return snap
+}
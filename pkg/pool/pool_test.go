@@ -0,0 +1,167 @@
+package pool_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-project-sprint-9/pkg/pool"
+)
+
+func TestPerWorkerSumMatchesTotalProcessed(t *testing.T) {
+	const workers = 4
+	const items = 200
+
+	p := pool.New[int64, int64](workers, 8, 8, func(v int64) int64 { return v })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.Start(ctx)
+
+	go func() {
+		for i := int64(0); i < items; i++ {
+			_ = p.Submit(ctx, i)
+		}
+		p.Close()
+	}()
+
+	var consumed int64
+	for range p.Results() {
+		consumed++
+	}
+
+	snap := p.Stats()
+	var sumPerWorker int64
+	for _, ws := range snap.PerWorker {
+		sumPerWorker += ws.Processed
+	}
+
+	if sumPerWorker != snap.Total {
+		t.Fatalf("sum(perWorker)=%d != Total=%d", sumPerWorker, snap.Total)
+	}
+	if consumed != items {
+		t.Fatalf("consumed=%d want=%d", consumed, items)
+	}
+	if sumPerWorker != items {
+		t.Fatalf("sumPerWorker=%d want=%d", sumPerWorker, items)
+	}
+}
+
+func TestNoMessageLossUnderCancellation(t *testing.T) {
+	const workers = 3
+	p := pool.New[int64, int64](workers, 4, 4, func(v int64) int64 { return v })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Start(ctx)
+
+	var submitted int64
+	submitDone := make(chan struct{})
+	go func() {
+		defer close(submitDone)
+		for i := int64(0); i < 1000; i++ {
+			if err := p.Submit(ctx, i); err != nil {
+				return
+			}
+			atomic.AddInt64(&submitted, 1)
+		}
+	}()
+
+	var consumed int64
+	resultsDone := make(chan struct{})
+	go func() {
+		defer close(resultsDone)
+		for range p.Results() {
+			atomic.AddInt64(&consumed, 1)
+		}
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	<-submitDone
+	// Results() only closes once every worker has actually returned, so
+	// by the time this unblocks no queue will gain or lose any more
+	// items: whatever is still sitting in a queue was dequeued by
+	// neither Results nor a dropped send.
+	<-resultsDone
+
+	var stillQueued, dropped int64
+	for _, ws := range p.Stats().PerWorker {
+		stillQueued += int64(ws.QueueLen)
+		dropped += ws.Dropped
+	}
+
+	if got, want := atomic.LoadInt64(&consumed)+stillQueued+dropped, atomic.LoadInt64(&submitted); got != want {
+		t.Fatalf("lost messages under cancellation: consumed=%d + stillQueued=%d + dropped=%d = %d, want submitted=%d",
+			atomic.LoadInt64(&consumed), stillQueued, dropped, got, want)
+	}
+}
+
+func TestWorkersDoNotBlockOnAbandonedResults(t *testing.T) {
+	// outBuf=0 means a worker with a finished result has nowhere to put
+	// it once nobody is reading Results() anymore — it must notice ctx
+	// is done and drop the result rather than block forever.
+	p := pool.New[int, int](2, 4, 0, func(v int) int { return v })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Start(ctx)
+
+	for i := 0; i < 4; i++ {
+		_ = p.Submit(ctx, i)
+	}
+	// Give workers a chance to dequeue and compute before anyone ever
+	// drains Results(), so they're parked trying to deliver.
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+
+	// Results() must still close once every worker has exited, even
+	// though nothing ever read from it.
+	select {
+	case _, ok := <-p.Results():
+		if ok {
+			t.Fatal("expected Results() to be closed with no further values")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Results() never closed: a worker is stuck delivering to an abandoned channel")
+	}
+}
+
+func TestRebalanceRedistributesSkewedQueues(t *testing.T) {
+	block := make(chan struct{})
+	p := pool.New[int, int](3, 50, 50, func(v int) int {
+		// Submit hands value i to worker i%3, so only the first worker's
+		// items block.
+		if v%3 == 0 {
+			<-block
+		}
+		return v
+	}, pool.WithRebalance[int, int](5))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.Start(ctx)
+
+	// Flood Submit's round-robin cycle so the first worker's queue
+	// would otherwise pile up relative to the others once its single
+	// in-flight call blocks.
+	for i := 0; i < 60; i++ {
+		_ = p.Submit(ctx, i)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	snap := p.Stats()
+	max, min := snap.PerWorker[0].QueueLen, snap.PerWorker[0].QueueLen
+	for _, ws := range snap.PerWorker {
+		if ws.QueueLen > max {
+			max = ws.QueueLen
+		}
+		if ws.QueueLen < min {
+			min = ws.QueueLen
+		}
+	}
+	close(block)
+
+	if max-min > 10 {
+		t.Fatalf("rebalance did not reduce skew: max=%d min=%d", max, min)
+	}
+}